@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+// Schematic is the authoring half of a ComponentDefinition/TraitDefinition
+// spec.template: the backend-specific description of how to render the
+// capability's CUE template. Exactly one of CUE, HELM or Template is
+// normally set; references/plugins/renderer.For resolves which applies.
+type Schematic struct {
+	// CUE is the built-in, hand-written CUE schematic.
+	CUE *CUE `json:"cue,omitempty"`
+	// HELM points at a Helm chart whose rendered manifests and
+	// values.schema.json back the capability.
+	HELM *Helm `json:"helm,omitempty"`
+	// Template is a Sprig-enabled Go template whose executed output is
+	// treated as the capability's CUE template.
+	Template *Template `json:"template,omitempty"`
+}
+
+// CUE is the CUE schematic backend.
+type CUE struct {
+	// Template is the raw CUE template body.
+	Template string `json:"template"`
+}
+
+// Helm is a reference to the Helm chart backing a capability, shared between
+// spec.template.helm (rendering) and spec.extension.install.helm (pulling a
+// chart dependency before the definition can be used).
+type Helm struct {
+	// Name is the chart name.
+	Name string `json:"name"`
+	// Version is the chart version constraint.
+	Version string `json:"version,omitempty"`
+	// URL is the chart repository URL the chart is fetched from.
+	URL string `json:"url"`
+}
+
+// Template is the Go-template schematic backend.
+type Template struct {
+	// Template is the raw Go template body.
+	Template string `json:"template"`
+}
+
+// DefinitionReference names the CRD a ComponentDefinition/TraitDefinition
+// wraps, resolved to its GroupVersionKind by util.GetGVKFromDefinition.
+type DefinitionReference struct {
+	// Name is the CRD's plural resource name, e.g. "deployments.apps".
+	Name string `json:"name"`
+	// Version is the CRD version to use when more than one is served.
+	Version string `json:"version,omitempty"`
+}
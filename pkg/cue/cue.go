@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cue evaluates a capability's CUE template and extracts its
+// `parameter` struct into the Capability's user-facing Parameters.
+package cue
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/pkg/errors"
+
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/references/utils/capabilities"
+)
+
+// parameterFieldName is the CUE struct a template exposes its user-facing
+// knobs under.
+const parameterFieldName = "parameter"
+
+// GetParameters compiles template with caps bound into its evaluation context
+// as the top-level `Capabilities` value, so the template can branch on e.g.
+// `if Capabilities.APIVersions["autoscaling/v2"] { ... }`, then walks the
+// resulting `parameter` struct into a flat Parameter list.
+func GetParameters(template string, caps capabilities.Capabilities) ([]types.Parameter, error) {
+	val := cuecontext.New().CompileString(capabilitiesBinding(caps) + "\n" + template)
+	if err := val.Err(); err != nil {
+		return nil, errors.Wrap(err, "compile cue template")
+	}
+
+	param := val.LookupPath(cue.ParsePath(parameterFieldName))
+	if !param.Exists() {
+		return nil, nil
+	}
+	if err := param.Err(); err != nil {
+		return nil, errors.Wrap(err, "lookup parameter field")
+	}
+	return paramsFromValue(param)
+}
+
+func paramsFromValue(v cue.Value) ([]types.Parameter, error) {
+	iter, err := v.Fields(cue.Optional(true))
+	if err != nil {
+		return nil, errors.Wrap(err, "iterate parameter fields")
+	}
+	var params []types.Parameter
+	for iter.Next() {
+		fv := iter.Value()
+		p := types.Parameter{
+			Name:     iter.Selector().String(),
+			Required: !iter.IsOptional(),
+			Type:     fv.IncompleteKind(),
+		}
+		if def, ok := fv.Default(); ok {
+			if b, err := def.MarshalJSON(); err == nil {
+				var out interface{}
+				if err := json.Unmarshal(b, &out); err == nil {
+					p.Default = out
+				}
+			}
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// capabilitiesBinding renders caps as the `Capabilities: {...}` CUE value the
+// compiled template is evaluated alongside.
+func capabilitiesBinding(caps capabilities.Capabilities) string {
+	b, _ := json.Marshal(caps.APIVersions)
+	return fmt.Sprintf(
+		"Capabilities: {\n\tKubeVersion: {Major: %q, Minor: %q, GitVersion: %q}\n\tVelaVersion: %q\n\tAPIVersions: %s\n}",
+		caps.KubeVersion.Major, caps.KubeVersion.Minor, caps.KubeVersion.GitVersion, caps.VelaVersion, string(b),
+	)
+}
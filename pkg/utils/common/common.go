@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package common holds the shared, process-wide configuration (cluster
+// connection, caches) that vela CLI commands thread through to the packages
+// that talk to a cluster or the outside world.
+package common
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/pkg/oam/discoverymapper"
+)
+
+// Args carries the cluster connection and the runtime options vela CLI
+// commands pass down into the packages under pkg/appfile and pkg/plugins.
+// It models only the fields those packages currently read; it is meant to
+// grow incrementally as more CLI-wide options are threaded through it, not
+// to be replaced wholesale.
+type Args struct {
+	// Config is the REST config of the target cluster.
+	Config *rest.Config
+	// Schema is the runtime scheme used to build Client.
+	Schema *runtime.Scheme
+
+	// DefinitionConcurrency bounds how many ComponentDefinition/TraitDefinition
+	// objects are handled in parallel by GetComponentsFromCluster and
+	// GetTraitsFromCluster. Zero/unset falls back to the package default.
+	DefinitionConcurrency int
+	// DefinitionTimeout is the deadline set on the context each definition's
+	// worker receives in GetComponentsFromCluster and GetTraitsFromCluster.
+	// Those workers do not pass that context into HandleDefinition or a Helm
+	// install, so a slow definition is not interrupted by it. Zero/unset
+	// falls back to the package default.
+	DefinitionTimeout time.Duration
+
+	// SkipVersionCompatibilityCheck bypasses the kubeVersion/velaVersion
+	// constraint annotations enforced against discovered definitions. Cluster
+	// capabilities are still discovered when it is set, and a discovery
+	// failure still fails the listing.
+	SkipVersionCompatibilityCheck bool
+
+	client client.Client
+	mapper discoverymapper.DiscoveryMapper
+}
+
+// GetClient returns a controller-runtime client for the target cluster,
+// building and caching it from c.Config/c.Schema on first use.
+func (c *Args) GetClient() (client.Client, error) {
+	if c.client != nil {
+		return c.client, nil
+	}
+	cli, err := client.New(c.Config, client.Options{Scheme: c.Schema})
+	if err != nil {
+		return nil, err
+	}
+	c.client = cli
+	return cli, nil
+}
+
+// GetDiscoveryMapper returns the discoverymapper.DiscoveryMapper for the
+// target cluster, building and caching it from c.Config on first use.
+func (c *Args) GetDiscoveryMapper() (discoverymapper.DiscoveryMapper, error) {
+	if c.mapper != nil {
+		return c.mapper, nil
+	}
+	dm, err := discoverymapper.New(c.Config)
+	if err != nil {
+		return nil, err
+	}
+	c.mapper = dm
+	return dm, nil
+}
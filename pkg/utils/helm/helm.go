@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package helm wraps the Helm SDK operations vela needs: installing a chart
+// dependency a trait/component definition declares, and loading a chart so
+// its templates/values/schema can be rendered into a capability.
+package helm
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/engine"
+
+	commontypes "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	util2 "github.com/oam-dev/kubevela/pkg/utils/util"
+)
+
+// InstallHelmChart pulls h's chart into the local Helm chart cache, so a
+// trait/component definition that declares it as an install dependency can
+// be applied. Output (download progress, warnings) is written to ioStream.
+func InstallHelmChart(ioStream util2.IOStreams, h *commontypes.Helm) error {
+	dl := downloader.ChartDownloader{
+		Out:              ioStream.Out,
+		Verify:           downloader.VerifyNever,
+		RepositoryConfig: chartutil.DefaultRepositoryConfigFile,
+		RepositoryCache:  chartutil.DefaultCacheDir,
+	}
+	_, _, err := dl.DownloadTo(h.URL, h.Version, chartutil.DefaultCacheDir)
+	if err != nil {
+		return errors.Wrapf(err, "download chart %s(%s) from %s", h.Name, h.Version, h.URL)
+	}
+	return nil
+}
+
+// Chart wraps a loaded Helm chart so it can be rendered outside of an actual
+// `helm install`/`helm template` invocation, backing references/plugins/renderer's
+// Helm-template schematic.
+type Chart struct {
+	chart *chart.Chart
+}
+
+// LoadChart loads the chart referenced by h, pulling it first via
+// InstallHelmChart on every call.
+func LoadChart(h *commontypes.Helm) (*Chart, error) {
+	if h == nil {
+		return nil, errors.New("helm schematic has no chart reference")
+	}
+	if err := InstallHelmChart(util2.IOStreams{}, h); err != nil {
+		return nil, err
+	}
+	path, err := chartutil.LocateChart(h.Name, &chartutil.ChartPathOptions{Version: h.Version, RepoURL: h.URL})
+	if err != nil {
+		return nil, errors.Wrapf(err, "locate chart %s(%s)", h.Name, h.Version)
+	}
+	c, err := loader.Load(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load chart %s", path)
+	}
+	return &Chart{chart: c}, nil
+}
+
+// Values returns the chart's own default values (Chart.yaml's values.yaml),
+// i.e. what `helm template` renders with when the caller overrides nothing.
+func (c *Chart) Values() map[string]interface{} {
+	return c.chart.Values
+}
+
+// Schema returns the chart's values.schema.json, or nil if it doesn't ship one.
+func (c *Chart) Schema() []byte {
+	return c.chart.Schema
+}
+
+// Render executes the chart's templates against vals, the same as `helm
+// template`, and returns every rendered manifest concatenated as a
+// `---`-separated multi-document YAML stream, in a deterministic (sorted by
+// template path) order. NOTES.txt and partials (`_*.tpl`) are not resources
+// and are skipped.
+func (c *Chart) Render(vals map[string]interface{}) (string, error) {
+	renderVals, err := chartutil.ToRenderValues(c.chart, vals, chartutil.ReleaseOptions{Name: c.chart.Name()}, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "compute render values")
+	}
+	rendered, err := engine.Render(c.chart, renderVals)
+	if err != nil {
+		return "", errors.Wrap(err, "render chart templates")
+	}
+
+	names := make([]string, 0, len(rendered))
+	for name := range rendered {
+		base := name[strings.LastIndex(name, "/")+1:]
+		if base == "NOTES.txt" || strings.HasPrefix(base, "_") {
+			continue
+		}
+		if strings.TrimSpace(rendered[name]) == "" {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(rendered[name])
+		b.WriteString("\n---\n")
+	}
+	return b.String(), nil
+}
@@ -21,8 +21,11 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,11 +40,118 @@ import (
 	"github.com/oam-dev/kubevela/pkg/utils/common"
 	"github.com/oam-dev/kubevela/pkg/utils/helm"
 	util2 "github.com/oam-dev/kubevela/pkg/utils/util"
+	"github.com/oam-dev/kubevela/references/plugins/renderer"
+	"github.com/oam-dev/kubevela/references/plugins/repo"
+	"github.com/oam-dev/kubevela/references/utils/capabilities"
+	"github.com/oam-dev/kubevela/references/utils/oci"
 )
 
 // DescriptionUndefined indicates the description is not defined
 const DescriptionUndefined = "description not defined"
 
+// defaultDefinitionConcurrency bounds how many definitions are handled in
+// parallel when c.DefinitionConcurrency is left unset.
+const defaultDefinitionConcurrency = 10
+
+// defaultDefinitionTimeout is the deadline set on the context each definition's
+// worker receives when c.DefinitionTimeout is left unset. The component and
+// trait workers do not pass that context into HandleDefinition or
+// validateCapabilities, so a slow definition (e.g. a blocking Helm install) is
+// not interrupted by it.
+const defaultDefinitionTimeout = 30 * time.Second
+
+// ProgressFunc is called as soon as a definition finishes processing without
+// a hard error, with the soft error (or nil) it produced. SyncDefinitionsToLocal
+// uses it to collect warnings, and returns them once both listings finish.
+type ProgressFunc func(definitionName string, err error)
+
+func definitionConcurrency(c common.Args) int {
+	if c.DefinitionConcurrency > 0 {
+		return c.DefinitionConcurrency
+	}
+	return defaultDefinitionConcurrency
+}
+
+func definitionTimeout(c common.Args) time.Duration {
+	if c.DefinitionTimeout > 0 {
+		return c.DefinitionTimeout
+	}
+	return defaultDefinitionTimeout
+}
+
+// handleDefinitionsConcurrently fans work across n definitions out to a
+// worker pool bounded by c's concurrency setting, honoring ctx cancellation
+// and giving each definition its own timeout. worker returns the resulting
+// capability together with a soft error (recorded as a partial failure, the
+// definition is skipped) and a hard error (aborts the whole listing, matching
+// the pre-existing `return nil, nil, err` behavior of a failed validateCapabilities
+// call). Order of the returned templates matches the order definitions were
+// submitted in.
+func handleDefinitionsConcurrently(ctx context.Context, c common.Args, n int, progress ProgressFunc,
+	worker func(ctx context.Context, i int) (types.Capability, string, error, error)) ([]types.Capability, []error, error) {
+	g, gctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, definitionConcurrency(c))
+	timeout := definitionTimeout(c)
+	results := make([]*types.Capability, n)
+
+	var mu sync.Mutex
+	var softErrors []error
+	stopped := false
+
+submit:
+	for i := 0; i < n; i++ {
+		i := i
+		select {
+		case sem <- struct{}{}:
+		case <-gctx.Done():
+			// either a previously submitted worker already failed and canceled
+			// gctx, or ctx itself was canceled; stop submitting but still drain
+			// the ones in flight below so none are abandoned, then surface the
+			// worker's actual error (or ctx's) instead of a truncated listing.
+			stopped = true
+			break submit
+		}
+		g.Go(func() error {
+			defer func() { <-sem }()
+			itemCtx, cancel := context.WithTimeout(gctx, timeout)
+			defer cancel()
+
+			tmp, name, softErr, hardErr := worker(itemCtx, i)
+			if hardErr != nil {
+				return hardErr
+			}
+			if softErr != nil {
+				mu.Lock()
+				softErrors = append(softErrors, softErr)
+				mu.Unlock()
+				if progress != nil {
+					progress(name, softErr)
+				}
+				return nil
+			}
+			results[i] = &tmp
+			if progress != nil {
+				progress(name, nil)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+	if stopped {
+		return nil, nil, ctx.Err()
+	}
+
+	var templates []types.Capability
+	for _, r := range results {
+		if r != nil {
+			templates = append(templates, *r)
+		}
+	}
+	return templates, softErrors, nil
+}
+
 // GetCapabilitiesFromCluster will get capability from K8s cluster
 func GetCapabilitiesFromCluster(ctx context.Context, namespace string, c common.Args, selector labels.Selector) ([]types.Capability, error) {
 	workloads, _, err := GetComponentsFromCluster(ctx, namespace, c, selector)
@@ -56,8 +166,89 @@ func GetCapabilitiesFromCluster(ctx context.Context, namespace string, c common.
 	return workloads, nil
 }
 
+// GetCapabilitiesFromOCIRegistry pulls a ComponentDefinition/TraitDefinition
+// bundle from an OCI-compliant registry (e.g. `oci://ghcr.io/org/webservice:v1`)
+// and converts it into a Capability, the same way GetCapabilitiesFromCluster
+// converts an in-cluster CRD and GetCapabilitiesFromRepo converts an entry
+// from a definition repository.
+func GetCapabilitiesFromOCIRegistry(ctx context.Context, ociRef string, name string) (types.Capability, error) {
+	bundle, err := oci.Pull(ctx, ociRef, "")
+	if err != nil {
+		return types.Capability{}, errors.Wrapf(err, "pull capability %s from oci registry", name)
+	}
+	tmp, err := appfile.ConvertTemplateJSON2Object(name, nil, nil)
+	if err != nil {
+		return types.Capability{}, err
+	}
+	tmp.Name = name
+	tmp.CueTemplateURI = ociRef
+	tmp.CueTemplate = bundle.CUETemplate
+	tmp.Source = &types.Source{ChartName: ""}
+	// an OCI-hosted definition is not discovered against a live cluster, so it
+	// is rendered without cluster capability guards.
+	tmp.Parameters, err = cue.GetParameters(tmp.CueTemplate, capabilities.Capabilities{})
+	if err != nil {
+		return types.Capability{}, err
+	}
+	return tmp, nil
+}
+
+// GetCapabilitiesFromRepo resolves capabilities published to a definition
+// repository server (see references/plugins/repo), the same way GetCapabilitiesFromCluster
+// resolves capabilities installed as CRDs. names restricts which capabilities
+// are resolved; a nil/empty names resolves every capability published to the
+// repository, each at its latest version.
+func GetCapabilitiesFromRepo(ctx context.Context, repoURL string, names []string) ([]types.Capability, error) {
+	client := &repo.Client{RepoURL: repoURL}
+	idx, err := client.LoadIndex(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load index from repository %s", repoURL)
+	}
+	if len(names) == 0 {
+		for name := range idx.Entries {
+			names = append(names, name)
+		}
+	}
+
+	var caps []types.Capability
+	for _, name := range names {
+		entry, err := client.Resolve(ctx, name, ">=0.0.0")
+		if err != nil {
+			return nil, err
+		}
+		cueTemplate, err := client.Fetch(ctx, entry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetch capability %s from repository %s", name, repoURL)
+		}
+		tmp, err := appfile.ConvertTemplateJSON2Object(name, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		tmp.Name = name
+		tmp.CueTemplate = cueTemplate
+		tmp.Description = entry.Description
+		tmp.AppliesTo = entry.AppliesTo
+		tmp.CrdInfo = entry.CRDInfo
+		tmp.Source = &types.Source{ChartName: ""}
+		tmp.Parameters, err = cue.GetParameters(tmp.CueTemplate, capabilities.Capabilities{})
+		if err != nil {
+			return nil, err
+		}
+		caps = append(caps, tmp)
+	}
+	return caps, nil
+}
+
 // GetComponentsFromCluster will get capability from K8s cluster
 func GetComponentsFromCluster(ctx context.Context, namespace string, c common.Args, selector labels.Selector) ([]types.Capability, []error, error) {
+	return GetComponentsFromClusterWithProgress(ctx, namespace, c, selector, nil)
+}
+
+// GetComponentsFromClusterWithProgress is GetComponentsFromCluster, fanning
+// definitions out across a bounded worker pool (see handleDefinitionsConcurrently)
+// instead of handling them one at a time, and reporting each definition via
+// progress as soon as it finishes so a caller can stream warnings.
+func GetComponentsFromClusterWithProgress(ctx context.Context, namespace string, c common.Args, selector labels.Selector, progress ProgressFunc) ([]types.Capability, []error, error) {
 	newClient, err := c.GetClient()
 	if err != nil {
 		return nil, nil, err
@@ -66,37 +257,50 @@ func GetComponentsFromCluster(ctx context.Context, namespace string, c common.Ar
 	if err != nil {
 		return nil, nil, err
 	}
+	caps, err := capabilities.Discover(dm)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "discover cluster capabilities")
+	}
 
-	var templates []types.Capability
 	var componentsDefs v1beta1.ComponentDefinitionList
 	err = newClient.List(ctx, &componentsDefs, &client.ListOptions{Namespace: namespace, LabelSelector: selector})
 	if err != nil {
 		return nil, nil, fmt.Errorf("list ComponentDefinition err: %w", err)
 	}
 
-	var templateErrors []error
-	for _, cd := range componentsDefs.Items {
-		ref, err := util.ConvertWorkloadGVK2Definition(dm, cd.Spec.Workload.Definition)
-		if err != nil {
-			templateErrors = append(templateErrors, errors.Wrapf(err, "convert workload definition `%s` failed", cd.Name))
-			continue
-		}
-		tmp, err := HandleDefinition(cd.Name, ref.Name, cd.Annotations, cd.Spec.Extension, types.TypeComponentDefinition, nil, cd.Spec.Schematic)
-		if err != nil {
-			templateErrors = append(templateErrors, errors.Wrapf(err, "handle workload template `%s` failed", cd.Name))
-			continue
-		}
-		tmp.Namespace = namespace
-		if tmp, err = validateCapabilities(tmp, dm, cd.Name, ref); err != nil {
-			return nil, nil, err
-		}
-		templates = append(templates, tmp)
-	}
-	return templates, templateErrors, nil
+	return handleDefinitionsConcurrently(ctx, c, len(componentsDefs.Items), progress,
+		func(ctx context.Context, i int) (types.Capability, string, error, error) {
+			cd := componentsDefs.Items[i]
+			ref, err := util.ConvertWorkloadGVK2Definition(dm, cd.Spec.Workload.Definition)
+			if err != nil {
+				return types.Capability{}, cd.Name, errors.Wrapf(err, "convert workload definition `%s` failed", cd.Name), nil
+			}
+			tmp, err := HandleDefinition(cd.Name, ref.Name, cd.Annotations, cd.Spec.Extension, types.TypeComponentDefinition, nil, cd.Spec.Schematic, caps)
+			if err != nil {
+				return types.Capability{}, cd.Name, errors.Wrapf(err, "handle workload template `%s` failed", cd.Name), nil
+			}
+			tmp.Namespace = namespace
+			var softErr error
+			if tmp, softErr, err = validateCapabilities(tmp, dm, cd.Name, ref, cd.Annotations, caps, c.SkipVersionCompatibilityCheck); err != nil {
+				return types.Capability{}, cd.Name, nil, err
+			}
+			if softErr != nil {
+				return types.Capability{}, cd.Name, softErr, nil
+			}
+			return tmp, cd.Name, nil, nil
+		})
 }
 
 // GetTraitsFromCluster will get capability from K8s cluster
 func GetTraitsFromCluster(ctx context.Context, namespace string, c common.Args, selector labels.Selector) ([]types.Capability, []error, error) {
+	return GetTraitsFromClusterWithProgress(ctx, namespace, c, selector, nil)
+}
+
+// GetTraitsFromClusterWithProgress is GetTraitsFromCluster, fanning
+// definitions out across a bounded worker pool instead of handling them one
+// at a time, and reporting each definition via progress as soon as it
+// finishes so a caller can stream warnings.
+func GetTraitsFromClusterWithProgress(ctx context.Context, namespace string, c common.Args, selector labels.Selector, progress ProgressFunc) ([]types.Capability, []error, error) {
 	newClient, err := c.GetClient()
 	if err != nil {
 		return nil, nil, err
@@ -105,37 +309,51 @@ func GetTraitsFromCluster(ctx context.Context, namespace string, c common.Args,
 	if err != nil {
 		return nil, nil, err
 	}
-	var templates []types.Capability
+	caps, err := capabilities.Discover(dm)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "discover cluster capabilities")
+	}
 	var traitDefs v1beta1.TraitDefinitionList
 	err = newClient.List(ctx, &traitDefs, &client.ListOptions{Namespace: namespace, LabelSelector: selector})
 	if err != nil {
 		return nil, nil, fmt.Errorf("list TraitDefinition err: %w", err)
 	}
 
-	var templateErrors []error
-	for _, td := range traitDefs.Items {
-		tmp, err := HandleDefinition(td.Name, td.Spec.Reference.Name, td.Annotations, td.Spec.Extension, types.TypeTrait, td.Spec.AppliesToWorkloads, td.Spec.Schematic)
-		if err != nil {
-			templateErrors = append(templateErrors, errors.Wrapf(err, "handle trait template `%s` failed", td.Name))
-			continue
-		}
-		tmp.Namespace = namespace
-		if tmp, err = validateCapabilities(tmp, dm, td.Name, td.Spec.Reference); err != nil {
-			return nil, nil, err
-		}
-		templates = append(templates, tmp)
-	}
-	return templates, templateErrors, nil
+	return handleDefinitionsConcurrently(ctx, c, len(traitDefs.Items), progress,
+		func(ctx context.Context, i int) (types.Capability, string, error, error) {
+			td := traitDefs.Items[i]
+			tmp, err := HandleDefinition(td.Name, td.Spec.Reference.Name, td.Annotations, td.Spec.Extension, types.TypeTrait, td.Spec.AppliesToWorkloads, td.Spec.Schematic, caps)
+			if err != nil {
+				return types.Capability{}, td.Name, errors.Wrapf(err, "handle trait template `%s` failed", td.Name), nil
+			}
+			tmp.Namespace = namespace
+			var softErr error
+			if tmp, softErr, err = validateCapabilities(tmp, dm, td.Name, td.Spec.Reference, td.Annotations, caps, c.SkipVersionCompatibilityCheck); err != nil {
+				return types.Capability{}, td.Name, nil, err
+			}
+			if softErr != nil {
+				return types.Capability{}, td.Name, softErr, nil
+			}
+			return tmp, td.Name, nil, nil
+		})
 }
 
-// validateCapabilities validates whether helm charts are successful installed, GVK are successfully retrieved.
-func validateCapabilities(tmp types.Capability, dm discoverymapper.DiscoveryMapper, definitionName string, reference commontypes.DefinitionReference) (types.Capability, error) {
+// validateCapabilities validates whether helm charts are successfully
+// installed, GVK are successfully retrieved, and (unless skipVersionCheck)
+// the definition's kubeVersion/velaVersion constraint annotations are
+// satisfied by caps — the single chokepoint both the component and trait
+// listing paths run every definition through before it's considered usable.
+// A version-constraint mismatch is reported as a soft error (the offending
+// definition is skipped, the rest of the listing proceeds); a Helm install
+// or GVK-resolution failure is a hard error (aborts the whole listing),
+// matching handleDefinitionsConcurrently's worker convention.
+func validateCapabilities(tmp types.Capability, dm discoverymapper.DiscoveryMapper, definitionName string, reference commontypes.DefinitionReference, annotation map[string]string, caps capabilities.Capabilities, skipVersionCheck bool) (types.Capability, error, error) {
 	var err error
 	if tmp.Install != nil {
 		tmp.Source = &types.Source{ChartName: tmp.Install.Helm.Name}
 		ioStream := util2.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
 		if err = helm.InstallHelmChart(ioStream, tmp.Install.Helm); err != nil {
-			return tmp, fmt.Errorf("unable to install helm chart dependency %s(%s from %s) for this trait '%s': %w ", tmp.Install.Helm.Name, tmp.Install.Helm.Version, tmp.Install.Helm.URL, definitionName, err)
+			return tmp, nil, fmt.Errorf("unable to install helm chart dependency %s(%s from %s) for this trait '%s': %w ", tmp.Install.Helm.Name, tmp.Install.Helm.Version, tmp.Install.Helm.URL, definitionName, err)
 		}
 	}
 	gvk, err := util.GetGVKFromDefinition(dm, reference)
@@ -145,20 +363,26 @@ func validateCapabilities(tmp types.Capability, dm discoverymapper.DiscoveryMapp
 		if strings.Contains(errMsg, substr) {
 			err = fmt.Errorf("expected provider: %s", strings.Split(errMsg, substr)[1])
 		}
-		return tmp, fmt.Errorf("installing capability '%s'... %w", definitionName, err)
+		return tmp, nil, fmt.Errorf("installing capability '%s'... %w", definitionName, err)
 	}
 	tmp.CrdInfo = &types.CRDInfo{
 		APIVersion: gvk.GroupVersion().String(),
 		Kind:       gvk.Kind,
 	}
 
-	return tmp, nil
+	if !skipVersionCheck {
+		if err := checkVersionCompatibility(definitionName, annotation, caps); err != nil {
+			return tmp, err, nil
+		}
+	}
+
+	return tmp, nil, nil
 }
 
 // HandleDefinition will handle definition to capability
-func HandleDefinition(name, crdName string, annotation map[string]string, extension *runtime.RawExtension, tp types.CapType, applyTo []string, schematic *commontypes.Schematic) (types.Capability, error) {
+func HandleDefinition(name, crdName string, annotation map[string]string, extension *runtime.RawExtension, tp types.CapType, applyTo []string, schematic *commontypes.Schematic, caps capabilities.Capabilities) (types.Capability, error) {
 	var tmp types.Capability
-	tmp, err := HandleTemplate(extension, schematic, name)
+	tmp, err := HandleTemplate(extension, schematic, name, caps)
 	if err != nil {
 		return types.Capability{}, err
 	}
@@ -185,65 +409,98 @@ func GetDescription(annotation map[string]string) string {
 }
 
 // HandleTemplate will handle definition template to capability
-func HandleTemplate(in *runtime.RawExtension, schematic *commontypes.Schematic, name string) (types.Capability, error) {
+func HandleTemplate(in *runtime.RawExtension, schematic *commontypes.Schematic, name string, caps capabilities.Capabilities) (types.Capability, error) {
 	tmp, err := appfile.ConvertTemplateJSON2Object(name, in, schematic)
 	if err != nil {
 		return types.Capability{}, err
 	}
 	tmp.Name = name
-	// if spec.template is not empty it should has the highest priority
-	if schematic != nil && schematic.CUE != nil {
-		tmp.CueTemplate = schematic.CUE.Template
+
+	// if spec.template declares one of the registered schematic backends
+	// (CUE, Helm-template, Go-template, ...) it has the highest priority.
+	if schematic != nil && (schematic.CUE != nil || schematic.HELM != nil || schematic.Template != nil) {
+		cueTemplate, params, err := renderer.GetParameters(schematic, caps)
+		if err != nil {
+			return types.Capability{}, err
+		}
+		tmp.CueTemplate = cueTemplate
 		tmp.CueTemplateURI = ""
+		tmp.Parameters = params
+		return tmp, nil
 	}
+
+	// legacy path: a CUE template fetched by reference rather than embedded
+	// in spec.template, from an in-cluster extension, an OCI registry, or a
+	// plain HTTP(S) URL.
 	if tmp.CueTemplateURI != "" {
-		b, err := common.HTTPGet(context.Background(), tmp.CueTemplateURI)
-		if err != nil {
-			return types.Capability{}, err
+		if oci.IsOCIRef(tmp.CueTemplateURI) {
+			bundle, err := oci.Pull(context.Background(), tmp.CueTemplateURI, "")
+			if err != nil {
+				return types.Capability{}, err
+			}
+			tmp.CueTemplate = bundle.CUETemplate
+		} else {
+			b, err := common.HTTPGet(context.Background(), tmp.CueTemplateURI)
+			if err != nil {
+				return types.Capability{}, err
+			}
+			tmp.CueTemplate = string(b)
 		}
-		tmp.CueTemplate = string(b)
 	}
 	if tmp.CueTemplate == "" {
 		return types.Capability{}, errors.New("template not exist in definition")
 	}
-	if err != nil {
-		return types.Capability{}, err
-	}
-	tmp.Parameters, err = cue.GetParameters(tmp.CueTemplate)
+	// caps is injected into the CUE evaluation context so the template can
+	// branch on cluster/vela version with e.g. `if Capabilities.APIVersions["autoscaling/v2"] { ... }`.
+	tmp.Parameters, err = cue.GetParameters(tmp.CueTemplate, caps)
 	if err != nil {
 		return types.Capability{}, err
 	}
 	return tmp, nil
 }
 
-// SyncDefinitionsToLocal sync definitions to local
-func SyncDefinitionsToLocal(ctx context.Context, c common.Args, localDefinitionDir string) ([]types.Capability, []string, error) {
+// SyncDefinitionsToLocal sync definitions to local. If repoURL is non-empty,
+// capabilities published to that definition repository (see references/plugins/repo)
+// are merged in alongside the cluster-sourced ones, so teams without cluster
+// admin rights can still distribute definitions to vela CLI users.
+func SyncDefinitionsToLocal(ctx context.Context, c common.Args, localDefinitionDir string, repoURL string) ([]types.Capability, []string, error) {
 	var syncedTemplates []types.Capability
 	var warnings []string
+	var mu sync.Mutex
+	onComponentProgress := func(kind string) ProgressFunc {
+		return func(name string, err error) {
+			if err == nil {
+				return
+			}
+			mu.Lock()
+			warnings = append(warnings, fmt.Sprintf("WARN: %v, you will unable to use this %s capability\n", err, kind))
+			mu.Unlock()
+		}
+	}
 
-	templates, templateErrors, err := GetComponentsFromCluster(ctx, types.DefaultKubeVelaNS, c, nil)
+	templates, _, err := GetComponentsFromClusterWithProgress(ctx, types.DefaultKubeVelaNS, c, nil, onComponentProgress("component"))
 	if err != nil {
 		return nil, nil, err
 	}
-	if len(templateErrors) > 0 {
-		for _, e := range templateErrors {
-			warnings = append(warnings, fmt.Sprintf("WARN: %v, you will unable to use this component capability\n", e))
-		}
-	}
 	syncedTemplates = append(syncedTemplates, templates...)
 	SinkTemp2Local(templates, localDefinitionDir)
 
-	templates, templateErrors, err = GetTraitsFromCluster(ctx, types.DefaultKubeVelaNS, c, nil)
+	templates, _, err = GetTraitsFromClusterWithProgress(ctx, types.DefaultKubeVelaNS, c, nil, onComponentProgress("trait"))
 	if err != nil {
 		return nil, warnings, err
 	}
-	if len(templateErrors) > 0 {
-		for _, e := range templateErrors {
-			warnings = append(warnings, fmt.Sprintf("WARN: %v, you will unable to use this trait capability\n", e))
-		}
-	}
 	syncedTemplates = append(syncedTemplates, templates...)
 	SinkTemp2Local(templates, localDefinitionDir)
+
+	if repoURL != "" {
+		repoTemplates, err := GetCapabilitiesFromRepo(ctx, repoURL, nil)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("WARN: %v, capabilities from repository %s will be unavailable\n", err, repoURL))
+		} else {
+			syncedTemplates = append(syncedTemplates, repoTemplates...)
+			SinkTemp2Local(repoTemplates, localDefinitionDir)
+		}
+	}
 	return syncedTemplates, warnings, nil
 }
 
@@ -270,8 +527,12 @@ func SyncDefinitionToLocal(ctx context.Context, c common.Args, localDefinitionDi
 		if err != nil {
 			return nil, err
 		}
+		caps, err := capabilities.Discover(dm)
+		if err != nil {
+			return nil, errors.Wrap(err, "discover cluster capabilities")
+		}
 		template, err := HandleDefinition(capabilityName, ref.Name,
-			componentDef.Annotations, componentDef.Spec.Extension, types.TypeComponentDefinition, nil, componentDef.Spec.Schematic)
+			componentDef.Annotations, componentDef.Spec.Extension, types.TypeComponentDefinition, nil, componentDef.Spec.Schematic, caps)
 		if err == nil {
 			return &template, nil
 		}
@@ -284,8 +545,16 @@ func SyncDefinitionToLocal(ctx context.Context, c common.Args, localDefinitionDi
 		foundCapability = true
 	}
 	if foundCapability {
+		dm, err := c.GetDiscoveryMapper()
+		if err != nil {
+			return nil, err
+		}
+		caps, err := capabilities.Discover(dm)
+		if err != nil {
+			return nil, errors.Wrap(err, "discover cluster capabilities")
+		}
 		template, err := HandleDefinition(capabilityName, traitDef.Spec.Reference.Name,
-			traitDef.Annotations, traitDef.Spec.Extension, types.TypeTrait, nil, traitDef.Spec.Schematic)
+			traitDef.Annotations, traitDef.Spec.Extension, types.TypeTrait, nil, traitDef.Spec.Schematic, caps)
 		if err == nil {
 			return &template, nil
 		}
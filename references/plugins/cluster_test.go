@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestHandleDefinitionsConcurrentlyAllSucceed(t *testing.T) {
+	c := common.Args{DefinitionConcurrency: 2}
+	var started int32
+	templates, softErrors, err := handleDefinitionsConcurrently(context.Background(), c, 5, nil,
+		func(ctx context.Context, i int) (types.Capability, string, error, error) {
+			atomic.AddInt32(&started, 1)
+			return types.Capability{Name: "cap"}, "cap", nil, nil
+		})
+	assert.NoError(t, err)
+	assert.Empty(t, softErrors)
+	assert.Len(t, templates, 5)
+	assert.EqualValues(t, 5, atomic.LoadInt32(&started))
+}
+
+func TestHandleDefinitionsConcurrentlySoftErrorIsPartialFailure(t *testing.T) {
+	c := common.Args{}
+	templates, softErrors, err := handleDefinitionsConcurrently(context.Background(), c, 3, nil,
+		func(ctx context.Context, i int) (types.Capability, string, error, error) {
+			if i == 1 {
+				return types.Capability{}, "broken", errors.New("broken"), nil
+			}
+			return types.Capability{Name: "cap"}, "cap", nil, nil
+		})
+	assert.NoError(t, err)
+	assert.Len(t, templates, 2)
+	assert.Len(t, softErrors, 1)
+}
+
+func TestHandleDefinitionsConcurrentlyHardErrorDrainsInFlightWorkers(t *testing.T) {
+	const n = 4
+	// concurrency >= n, and item 0 fails only once every worker has started,
+	// so every worker is dispatched before item 0's hard error cancels gctx,
+	// exercising the in-flight drain rather than the submission-blocked branch.
+	c := common.Args{DefinitionConcurrency: n}
+	wantErr := errors.New("hard failure")
+	var started, finished int32
+	_, _, err := handleDefinitionsConcurrently(context.Background(), c, n, nil,
+		func(ctx context.Context, i int) (types.Capability, string, error, error) {
+			defer atomic.AddInt32(&finished, 1)
+			atomic.AddInt32(&started, 1)
+			if i == 0 {
+				for atomic.LoadInt32(&started) < n {
+					time.Sleep(time.Millisecond)
+				}
+				return types.Capability{}, "bad", nil, wantErr
+			}
+			time.Sleep(10 * time.Millisecond)
+			return types.Capability{Name: "cap"}, "cap", nil, nil
+		})
+	assert.Equal(t, wantErr, errors.Cause(err))
+	assert.EqualValues(t, n, atomic.LoadInt32(&finished), "every dispatched worker must be drained, not abandoned")
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+
+	"github.com/oam-dev/kubevela/references/utils/capabilities"
+)
+
+// AnnKubeVersionConstraint, set on a ComponentDefinition/TraitDefinition,
+// holds a semver range (e.g. `>=1.22.0 <1.28.0`) the target cluster's
+// Kubernetes version must satisfy for the definition to be usable.
+const AnnKubeVersionConstraint = "definition.oam.dev/kubeVersion"
+
+// AnnVelaVersionConstraint is the equivalent constraint against the running
+// vela version, letting definition authors require e.g. `>=1.6.0` CUE
+// features.
+const AnnVelaVersionConstraint = "definition.oam.dev/velaVersion"
+
+// checkVersionCompatibility validates the kubeVersion/velaVersion constraint
+// annotations (if present) of a definition against the discovered cluster
+// capabilities, returning an error naming the definition and the failing
+// constraint if either does not match.
+func checkVersionCompatibility(definitionName string, annotation map[string]string, caps capabilities.Capabilities) error {
+	if annotation == nil {
+		return nil
+	}
+	if err := checkConstraint(annotation[AnnKubeVersionConstraint], caps.KubeVersion.GitVersion); err != nil {
+		return errors.Wrapf(err, "capability %s requires kubeVersion %s", definitionName, annotation[AnnKubeVersionConstraint])
+	}
+	if err := checkConstraint(annotation[AnnVelaVersionConstraint], caps.VelaVersion); err != nil {
+		return errors.Wrapf(err, "capability %s requires velaVersion %s", definitionName, annotation[AnnVelaVersionConstraint])
+	}
+	return nil
+}
+
+func checkConstraint(constraint string, version string) error {
+	if constraint == "" {
+		return nil
+	}
+	cst, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return errors.Wrap(err, "parse version constraint")
+	}
+	v, err := semver.NewVersion(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return errors.Wrapf(err, "parse version %s", version)
+	}
+	if !cst.Check(v) {
+		return errors.Errorf("current version %s does not satisfy it", version)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oam-dev/kubevela/references/utils/capabilities"
+)
+
+func TestCheckConstraint(t *testing.T) {
+	cases := map[string]struct {
+		constraint string
+		version    string
+		wantErr    bool
+	}{
+		"empty constraint always matches": {constraint: "", version: "v1.20.0"},
+		"version within range":            {constraint: ">=1.22.0 <1.28.0", version: "v1.24.3"},
+		"version below range":             {constraint: ">=1.22.0 <1.28.0", version: "v1.20.0", wantErr: true},
+		"version at or above upper bound": {constraint: ">=1.22.0 <1.28.0", version: "v1.28.0", wantErr: true},
+		"invalid constraint":              {constraint: "not-a-constraint", version: "v1.24.0", wantErr: true},
+		"invalid version":                 {constraint: ">=1.22.0", version: "not-a-version", wantErr: true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := checkConstraint(c.constraint, c.version)
+			if c.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckVersionCompatibility(t *testing.T) {
+	caps := capabilities.Capabilities{
+		KubeVersion: capabilities.KubeVersion{GitVersion: "v1.24.0"},
+		VelaVersion: "v1.6.0",
+	}
+
+	assert.NoError(t, checkVersionCompatibility("my-trait", nil, caps))
+
+	assert.NoError(t, checkVersionCompatibility("my-trait", map[string]string{
+		AnnKubeVersionConstraint: ">=1.22.0",
+		AnnVelaVersionConstraint: ">=1.6.0",
+	}, caps))
+
+	err := checkVersionCompatibility("my-trait", map[string]string{
+		AnnKubeVersionConstraint: ">=1.28.0",
+	}, caps)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "my-trait")
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package renderer
+
+import (
+	"github.com/pkg/errors"
+
+	commontypes "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/cue"
+	"github.com/oam-dev/kubevela/references/utils/capabilities"
+)
+
+func init() {
+	Register(TypeCUE, func() SchematicRenderer { return &cueRenderer{} })
+}
+
+// cueRenderer is the built-in, original schematic backend: the template is
+// already CUE, so rendering is a pass-through.
+type cueRenderer struct{}
+
+func (r *cueRenderer) Render(schematic *commontypes.Schematic) (string, error) {
+	if schematic == nil || schematic.CUE == nil {
+		return "", errors.New("schematic has no CUE template")
+	}
+	return schematic.CUE.Template, nil
+}
+
+func (r *cueRenderer) GetParameters(cueTemplate string, caps capabilities.Capabilities) ([]types.Parameter, error) {
+	return cue.GetParameters(cueTemplate, caps)
+}
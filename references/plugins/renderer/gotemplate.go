@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package renderer
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/pkg/errors"
+
+	commontypes "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/cue"
+	"github.com/oam-dev/kubevela/references/utils/capabilities"
+)
+
+func init() {
+	Register(TypeGoTemplate, func() SchematicRenderer { return &goTemplateRenderer{} })
+}
+
+// goTemplateRenderer lets definition authors who prefer Sprig-style templates
+// over CUE author schematic.Template instead: the template is executed and
+// its output is treated as the capability's CUE template, the same as a
+// hand-written one.
+type goTemplateRenderer struct{}
+
+func (r *goTemplateRenderer) Render(schematic *commontypes.Schematic) (string, error) {
+	if schematic == nil || schematic.Template == nil {
+		return "", errors.New("schematic has no Go template")
+	}
+	tpl, err := template.New("schematic").Funcs(sprig.TxtFuncMap()).Parse(schematic.Template.Template)
+	if err != nil {
+		return "", errors.Wrap(err, "parse go template")
+	}
+	var out strings.Builder
+	if err := tpl.Execute(&out, nil); err != nil {
+		return "", errors.Wrap(err, "execute go template")
+	}
+	return out.String(), nil
+}
+
+func (r *goTemplateRenderer) GetParameters(cueTemplate string, caps capabilities.Capabilities) ([]types.Parameter, error) {
+	return cue.GetParameters(cueTemplate, caps)
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	commontypes "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/cue"
+	"github.com/oam-dev/kubevela/pkg/utils/helm"
+	"github.com/oam-dev/kubevela/references/utils/capabilities"
+)
+
+func init() {
+	Register(TypeHelm, func() SchematicRenderer { return &helmRenderer{} })
+}
+
+// helmRenderer loads a chart referenced by schematic.Helm, renders it with
+// the chart's own default values to produce the capability's `output:` CUE
+// block, and synthesizes a `parameter:` block from values.schema.json,
+// letting a chart author's existing JSON schema double as the capability's
+// Parameters without having to hand-write a CUE template.
+type helmRenderer struct{}
+
+func (r *helmRenderer) Render(schematic *commontypes.Schematic) (string, error) {
+	if schematic == nil || schematic.HELM == nil {
+		return "", errors.New("schematic has no Helm chart reference")
+	}
+	chart, err := helm.LoadChart(schematic.HELM)
+	if err != nil {
+		return "", errors.Wrap(err, "load helm chart")
+	}
+
+	manifest, err := chart.Render(chart.Values())
+	if err != nil {
+		return "", errors.Wrap(err, "render helm chart templates")
+	}
+	output, err := manifestToCUE(manifest)
+	if err != nil {
+		return "", errors.Wrap(err, "convert rendered manifest to CUE")
+	}
+
+	param := "parameter: {}"
+	if schemaBytes := chart.Schema(); len(schemaBytes) > 0 {
+		var schema map[string]interface{}
+		if err := json.Unmarshal(schemaBytes, &schema); err != nil {
+			return "", errors.Wrap(err, "parse values.schema.json")
+		}
+		param = "parameter: " + jsonSchemaToCUE(schema)
+	}
+	return param + "\n" + output, nil
+}
+
+func (r *helmRenderer) GetParameters(cueTemplate string, caps capabilities.Capabilities) ([]types.Parameter, error) {
+	return cue.GetParameters(cueTemplate, caps)
+}
+
+// jsonSchemaToCUE renders a (possibly nested) JSON schema object as a CUE
+// struct literal, mapping the common JSON Schema primitive types to their CUE
+// equivalents. It only needs to be good enough to drive Parameter extraction,
+// not to losslessly round-trip arbitrary schemas.
+func jsonSchemaToCUE(schema map[string]interface{}) string {
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return "{}"
+	}
+	required := map[string]bool{}
+	if req, ok := schema["required"].([]interface{}); ok {
+		for _, r := range req {
+			if s, ok := r.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range names {
+		prop, _ := properties[name].(map[string]interface{})
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		fmt.Fprintf(&b, "\t%s%s: %s\n", name, optional, jsonSchemaTypeToCUE(prop))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func jsonSchemaTypeToCUE(prop map[string]interface{}) string {
+	switch t, _ := prop["type"].(string); t {
+	case "integer":
+		return "int"
+	case "number":
+		return "number"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[...]"
+	case "object":
+		if nested, ok := prop["properties"].(map[string]interface{}); ok {
+			return jsonSchemaToCUE(map[string]interface{}{"properties": nested, "required": prop["required"]})
+		}
+		return "{...}"
+	default:
+		return "string"
+	}
+}
+
+// manifestToCUE takes the (possibly multi-document, `---`-separated) YAML
+// manifest rendered from a Helm chart's templates and renders it as the
+// capability's `output`/`outputs` CUE blocks: the first non-empty document
+// becomes `output`, and any further documents become additional entries
+// under `outputs`, keyed by their position, so that charts which render
+// more than one resource (e.g. a Deployment plus a Service) don't silently
+// lose everything but the first.
+func manifestToCUE(manifest string) (string, error) {
+	var objs []map[string]interface{}
+	for _, doc := range strings.Split(manifest, "\n---\n") {
+		doc = strings.TrimSpace(doc)
+		if doc == "" {
+			continue
+		}
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return "", errors.Wrap(err, "parse rendered manifest")
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+	if len(objs) == 0 {
+		return "", errors.New("helm chart rendered no resource manifest")
+	}
+
+	out := "output: " + valueToCUE(objs[0])
+	if len(objs) > 1 {
+		var b strings.Builder
+		b.WriteString("\noutputs: {\n")
+		for i, obj := range objs[1:] {
+			fmt.Fprintf(&b, "\tresource%d: %s\n", i+1, valueToCUE(obj))
+		}
+		b.WriteString("}")
+		out += b.String()
+	}
+	return out, nil
+}
+
+// cueFieldName renders a map key as a CUE struct field name, quoting it when
+// it isn't a valid bare CUE identifier. Rendered Kubernetes manifests
+// routinely carry keys like "app.kubernetes.io/managed-by" or
+// "helm.sh/chart" (standard Helm label/annotation conventions), which are
+// not valid unquoted CUE field names.
+func cueFieldName(name string) string {
+	for i, r := range name {
+		isLetter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if isLetter || (i > 0 && isDigit) {
+			continue
+		}
+		return strconv.Quote(name)
+	}
+	if name == "" {
+		return strconv.Quote(name)
+	}
+	return name
+}
+
+// valueToCUE renders an arbitrary decoded YAML/JSON value as a CUE literal.
+// yaml.v2 decodes mapping nodes as map[interface{}]interface{}, so that case
+// is normalized to map[string]interface{} before recursing.
+func valueToCUE(v interface{}) string {
+	switch val := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, vv := range val {
+			m[fmt.Sprintf("%v", k)] = vv
+		}
+		return valueToCUE(m)
+	case map[string]interface{}:
+		names := make([]string, 0, len(val))
+		for name := range val {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, name := range names {
+			fmt.Fprintf(&b, "\t%s: %s\n", cueFieldName(name), valueToCUE(val[name]))
+		}
+		b.WriteString("}")
+		return b.String()
+	case []interface{}:
+		items := make([]string, len(val))
+		for i, item := range val {
+			items[i] = valueToCUE(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case string:
+		return strconv.Quote(val)
+	case bool:
+		return strconv.FormatBool(val)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
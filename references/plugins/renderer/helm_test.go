@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package renderer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchemaToCUE(t *testing.T) {
+	const schemaJSON = `{
+		"properties": {
+			"replicas": {"type": "integer"},
+			"enabled": {"type": "boolean"},
+			"name": {"type": "string"},
+			"tags": {"type": "array"},
+			"resources": {
+				"type": "object",
+				"properties": {
+					"limit": {"type": "string"}
+				}
+			}
+		},
+		"required": ["name"]
+	}`
+	var schema map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(schemaJSON), &schema))
+
+	cue := jsonSchemaToCUE(schema)
+
+	assert.Contains(t, cue, "name: string")
+	assert.Contains(t, cue, "replicas?: int")
+	assert.Contains(t, cue, "enabled?: bool")
+	assert.Contains(t, cue, "tags?: [...]")
+	assert.Contains(t, cue, "resources?: {")
+	assert.Contains(t, cue, "limit?: string")
+}
+
+func TestJSONSchemaToCUEEmptyProperties(t *testing.T) {
+	assert.Equal(t, "{}", jsonSchemaToCUE(map[string]interface{}{}))
+}
+
+func TestManifestToCUESkipsLeadingEmptyDocuments(t *testing.T) {
+	manifest := "\n---\n" + `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: demo
+`
+	out, err := manifestToCUE(manifest)
+	assert.NoError(t, err)
+	assert.Contains(t, out, `output: {`)
+	assert.Contains(t, out, `kind: "ConfigMap"`)
+	assert.Contains(t, out, `name: "demo"`)
+}
+
+func TestManifestToCUENoResource(t *testing.T) {
+	_, err := manifestToCUE("\n---\n\n---\n")
+	assert.Error(t, err)
+}
+
+func TestManifestToCUEKeepsFurtherDocumentsAsOutputs(t *testing.T) {
+	manifest := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: demo
+` + "\n---\n" + `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: other
+`
+	out, err := manifestToCUE(manifest)
+	assert.NoError(t, err)
+	assert.Contains(t, out, `output: {`)
+	assert.Contains(t, out, `kind: "ConfigMap"`)
+	assert.Contains(t, out, "outputs: {")
+	assert.Contains(t, out, "resource1: {")
+	assert.Contains(t, out, `kind: "Secret"`)
+}
+
+func TestValueToCUEQuotesNonIdentifierKeys(t *testing.T) {
+	v := map[string]interface{}{
+		"app.kubernetes.io/managed-by": "Helm",
+		"name":                         "demo",
+	}
+	out := valueToCUE(v)
+	assert.Contains(t, out, `"app.kubernetes.io/managed-by": "Helm"`)
+	assert.Contains(t, out, `name: "demo"`)
+}
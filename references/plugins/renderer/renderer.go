@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package renderer turns a ComponentDefinition/TraitDefinition schematic into
+// the CUE template body and Parameters the rest of the Capability pipeline
+// operates on. The CUE schematic is one built-in SchematicRenderer among
+// others (Helm-template, Go-template); third parties can plug in their own
+// authoring format (e.g. jsonnet, kustomize) by calling Register at init time.
+package renderer
+
+import (
+	"github.com/pkg/errors"
+
+	commontypes "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/references/utils/capabilities"
+)
+
+// Type identifies a schematic backend.
+type Type string
+
+const (
+	// TypeCUE is the built-in CUE schematic backend.
+	TypeCUE Type = "cue"
+	// TypeHelm renders a Helm chart (schematic.Helm) into a capability.
+	TypeHelm Type = "helm"
+	// TypeGoTemplate renders a Sprig-enabled Go template into a capability.
+	TypeGoTemplate Type = "go-template"
+)
+
+// SchematicRenderer is the extension point every schematic backend
+// implements: given the schematic half of a definition, produce the CUE
+// template body and the Parameters extracted from it.
+type SchematicRenderer interface {
+	// Render converts schematic into the CUE template body the rest of the
+	// Capability pipeline (parameter extraction, application rendering)
+	// operates on.
+	Render(schematic *commontypes.Schematic) (string, error)
+	// GetParameters extracts the Capability's user-facing Parameters from the
+	// rendered CUE template, with caps injected for any `if Capabilities....`
+	// guards the template declares.
+	GetParameters(cueTemplate string, caps capabilities.Capabilities) ([]types.Parameter, error)
+}
+
+// Factory constructs a new SchematicRenderer instance.
+type Factory func() SchematicRenderer
+
+var registry = map[Type]Factory{}
+
+// Register adds a SchematicRenderer factory under name. Third-party backends
+// call this from an init() func to make themselves available to HandleTemplate.
+func Register(name Type, factory Factory) {
+	registry[name] = factory
+}
+
+// For resolves which renderer applies to schematic. CUE remains the default
+// when schematic declares more than one backend, preserving the historical
+// "spec.template has the highest priority" behavior.
+func For(schematic *commontypes.Schematic) (SchematicRenderer, error) {
+	t := TypeCUE
+	switch {
+	case schematic == nil:
+	case schematic.CUE != nil:
+		t = TypeCUE
+	case schematic.HELM != nil:
+		t = TypeHelm
+	case schematic.Template != nil:
+		t = TypeGoTemplate
+	}
+	factory, ok := registry[t]
+	if !ok {
+		return nil, errors.Errorf("no schematic renderer registered for %s", t)
+	}
+	return factory(), nil
+}
+
+// GetParameters resolves the renderer for schematic, renders it to a CUE
+// template, and extracts its Parameters. This is the renderer-aware
+// replacement for calling cue.GetParameters directly.
+func GetParameters(schematic *commontypes.Schematic, caps capabilities.Capabilities) (string, []types.Parameter, error) {
+	r, err := For(schematic)
+	if err != nil {
+		return "", nil, err
+	}
+	cueTemplate, err := r.Render(schematic)
+	if err != nil {
+		return "", nil, err
+	}
+	params, err := r.GetParameters(cueTemplate, caps)
+	if err != nil {
+		return "", nil, err
+	}
+	return cueTemplate, params, nil
+}
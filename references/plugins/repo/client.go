@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+// Client resolves and downloads capability definitions published by a Server.
+type Client struct {
+	// RepoURL is the base URL of the repository, e.g. `https://repo.example.com`.
+	RepoURL string
+	// PublicKeyring, if set, is used to verify the detached signature of the
+	// fetched index.yaml.
+	PublicKeyring string
+}
+
+// LoadIndex fetches and parses the repository's index.yaml, verifying its
+// detached signature first if c.PublicKeyring is set.
+func (c *Client) LoadIndex(ctx context.Context) (*IndexFile, error) {
+	indexBytes, err := c.get(ctx, "/"+IndexFileName)
+	if err != nil {
+		return nil, err
+	}
+	if c.PublicKeyring != "" {
+		sigBytes, err := c.get(ctx, "/"+IndexFileName+".asc")
+		if err != nil {
+			return nil, errors.Wrap(err, "fetch index signature")
+		}
+		if err := c.verifySignature(indexBytes, sigBytes); err != nil {
+			return nil, err
+		}
+	}
+	return LoadIndexFile(indexBytes)
+}
+
+// Resolve finds the entry for name matching the given semver constraint
+// (e.g. `>=1.0.0 <2.0.0`), returning the highest version that satisfies it.
+func (c *Client) Resolve(ctx context.Context, name, constraint string) (*Entry, error) {
+	idx, err := c.LoadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions, ok := idx.Entries[name]
+	if !ok || len(versions) == 0 {
+		return nil, errors.Errorf("capability %s not found in repository %s", name, c.RepoURL)
+	}
+	cst, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse version constraint %s", constraint)
+	}
+	var best *Entry
+	var bestVersion *semver.Version
+	for i := range versions {
+		v, err := semver.NewVersion(versions[i].Version)
+		if err != nil {
+			continue
+		}
+		if !cst.Check(v) {
+			continue
+		}
+		if bestVersion == nil || v.GreaterThan(bestVersion) {
+			bestVersion = v
+			best = &versions[i]
+		}
+	}
+	if best == nil {
+		return nil, errors.Errorf("no version of %s satisfies %s in repository %s", name, constraint, c.RepoURL)
+	}
+	return best, nil
+}
+
+// Fetch downloads and unpacks the CUE template for entry, verifying its
+// sha256 digest against entry.Digest.
+func (c *Client) Fetch(ctx context.Context, entry *Entry) (string, error) {
+	tarball, err := c.getURL(ctx, entry.URL)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(tarball)
+	if got := "sha256:" + hex.EncodeToString(sum[:]); got != entry.Digest {
+		return "", errors.Errorf("digest mismatch for %s: expected %s, got %s", entry.Name, entry.Digest, got)
+	}
+	return extractCUETemplate(tarball)
+}
+
+func extractCUETemplate(tarball []byte) (string, error) {
+	gzr, err := gzip.NewReader(strings.NewReader(string(tarball)))
+	if err != nil {
+		return "", errors.Wrap(err, "unpack definition tarball")
+	}
+	defer gzr.Close() //nolint:errcheck
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if hdr.Name == "definition.cue" {
+			b, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		}
+	}
+	return "", errors.New("no definition.cue found in definition tarball")
+}
+
+func (c *Client) verifySignature(data, sig []byte) error {
+	keyringFile, err := os.Open(c.PublicKeyring) //nolint:gosec // operator-provided keyring path
+	if err != nil {
+		return errors.Wrap(err, "open public keyring")
+	}
+	defer keyringFile.Close() //nolint:errcheck
+	keyring, err := openpgp.ReadArmoredKeyRing(keyringFile)
+	if err != nil {
+		return errors.Wrap(err, "read public keyring")
+	}
+	_, err = openpgp.CheckArmoredDetachedSignature(keyring, strings.NewReader(string(data)), strings.NewReader(string(sig)), nil)
+	if err != nil {
+		return errors.Wrap(err, "verify index signature")
+	}
+	return nil
+}
+
+func (c *Client) get(ctx context.Context, path string) ([]byte, error) {
+	return c.getURL(ctx, strings.TrimSuffix(c.RepoURL, "/")+path)
+}
+
+func (c *Client) getURL(ctx context.Context, url string) ([]byte, error) {
+	return common.HTTPGet(ctx, url)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package repo implements an HTTP repository for distributing KubeVela
+// ComponentDefinition/TraitDefinition bundles (CUE template plus metadata):
+// a Server publishes a directory of definitions as a signed, indexed,
+// versioned set of tarballs, and a Client resolves and fetches them by
+// capability name and semver constraint, so operators can share definitions
+// with vela CLI users who don't have cluster admin rights.
+package repo
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/oam-dev/kubevela/apis/types"
+)
+
+// APIVersion is the schema version of the index file this package produces
+// and consumes.
+const APIVersion = "v1"
+
+// IndexFileName is the well-known name the index is published under, e.g.
+// `https://repo.example.com/index.yaml`.
+const IndexFileName = "index.yaml"
+
+// Entry describes one version of one capability published to a repository.
+type Entry struct {
+	Name        string         `yaml:"name"`
+	Version     string         `yaml:"version"`
+	Digest      string         `yaml:"digest"`
+	URL         string         `yaml:"url"`
+	Description string         `yaml:"description,omitempty"`
+	AppliesTo   []string       `yaml:"appliesTo,omitempty"`
+	CRDInfo     *types.CRDInfo `yaml:"crdInfo,omitempty"`
+}
+
+// IndexFile is the `name -> [{version, digest, url, ...}]` manifest served
+// at the repository root, analogous to a Helm chart repo's index.yaml.
+type IndexFile struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Generated  time.Time          `yaml:"generated"`
+	Entries    map[string][]Entry `yaml:"entries"`
+}
+
+// NewIndexFile returns an empty IndexFile ready to be populated with Add.
+func NewIndexFile() *IndexFile {
+	return &IndexFile{APIVersion: APIVersion, Entries: map[string][]Entry{}}
+}
+
+// Add registers one capability version in the index.
+func (i *IndexFile) Add(entry Entry) {
+	i.Entries[entry.Name] = append(i.Entries[entry.Name], entry)
+}
+
+// Marshal renders the index file as YAML.
+func (i *IndexFile) Marshal() ([]byte, error) {
+	return yaml.Marshal(i)
+}
+
+// LoadIndexFile parses an index.yaml previously produced by Marshal.
+func LoadIndexFile(data []byte) (*IndexFile, error) {
+	var idx IndexFile
+	if err := yaml.Unmarshal(data, &idx); err != nil {
+		return nil, errors.Wrap(err, "parse repository index")
+	}
+	if idx.Entries == nil {
+		idx.Entries = map[string][]Entry{}
+	}
+	return &idx, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repo
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Server publishes a directory of KubeVela definitions (one sub-directory
+// per capability, each containing a `definition.cue` template and a
+// `metadata.yaml` describing it) as a versioned, indexed HTTP repository.
+type Server struct {
+	// Dir is the directory of definitions to publish.
+	Dir string
+	// PrivateKeyring, if set, is used to produce a detached PGP signature of
+	// the generated index.yaml.
+	PrivateKeyring string
+}
+
+type definitionMetadata struct {
+	Name        string   `yaml:"name"`
+	Version     string   `yaml:"version"`
+	Description string   `yaml:"description"`
+	AppliesTo   []string `yaml:"appliesTo"`
+}
+
+func parseMetadata(data []byte) (*definitionMetadata, error) {
+	var meta definitionMetadata
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return nil, errors.Wrap(err, "parse metadata.yaml")
+	}
+	return &meta, nil
+}
+
+// BuildIndex walks s.Dir and produces an IndexFile describing every
+// capability version found, keyed by capability name, each entry recording
+// the sha256 digest of its packaged tarball.
+func (s *Server) BuildIndex(baseURL string) (*IndexFile, error) {
+	entries, err := ioutil.ReadDir(s.Dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read definitions directory %s", s.Dir)
+	}
+	idx := NewIndexFile()
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		meta, tarball, err := s.packageDefinition(e.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "package definition %s", e.Name())
+		}
+		digest := sha256.Sum256(tarball)
+		idx.Add(Entry{
+			Name:        meta.Name,
+			Version:     meta.Version,
+			Digest:      "sha256:" + hex.EncodeToString(digest[:]),
+			URL:         fmt.Sprintf("%s/%s-%s.tgz", strings.TrimSuffix(baseURL, "/"), meta.Name, meta.Version),
+			Description: meta.Description,
+			AppliesTo:   meta.AppliesTo,
+		})
+	}
+	return idx, nil
+}
+
+// packageDefinition tars up the `name` sub-directory of s.Dir and returns its
+// parsed metadata alongside the gzipped tarball bytes.
+func (s *Server) packageDefinition(name string) (*definitionMetadata, []byte, error) {
+	dir := filepath.Join(s.Dir, name)
+	metaBytes, err := ioutil.ReadFile(filepath.Join(dir, "metadata.yaml")) //nolint:gosec // dir is enumerated from s.Dir, not user input
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "read metadata.yaml")
+	}
+	meta, err := parseMetadata(metaBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var buf strings.Builder
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadFile(path) //nolint:gosec // path is derived from filepath.Walk over dir
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{Name: rel, Mode: 0640, Size: int64(len(content))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err = tw.Write(content)
+		return err
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "package definition contents")
+	}
+	if err := tw.Close(); err != nil {
+		return nil, nil, err
+	}
+	if err := gzw.Close(); err != nil {
+		return nil, nil, err
+	}
+	return meta, []byte(buf.String()), nil
+}
+
+// Sign produces a detached, armored PGP signature of data using s.PrivateKeyring.
+func (s *Server) Sign(data []byte) ([]byte, error) {
+	keyringFile, err := os.Open(s.PrivateKeyring) //nolint:gosec // operator-provided keyring path
+	if err != nil {
+		return nil, errors.Wrap(err, "open private keyring")
+	}
+	defer keyringFile.Close() //nolint:errcheck
+	entityList, err := openpgp.ReadKeyRing(keyringFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "read private keyring")
+	}
+	var sig strings.Builder
+	if err := openpgp.ArmoredDetachSign(&sig, entityList[0], strings.NewReader(string(data)), nil); err != nil {
+		return nil, errors.Wrap(err, "sign index")
+	}
+	return []byte(sig.String()), nil
+}
+
+// Handler serves the index (and its detached signature, if PrivateKeyring is
+// set) at `/index.yaml` / `/index.yaml.asc`, and each packaged definition
+// tarball at `/{name}-{version}.tgz`.
+func (s *Server) Handler(baseURL string) (http.Handler, error) {
+	idx, err := s.BuildIndex(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	indexBytes, err := idx.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+IndexFileName, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(indexBytes)
+	})
+	if s.PrivateKeyring != "" {
+		sig, err := s.Sign(indexBytes)
+		if err != nil {
+			return nil, err
+		}
+		mux.HandleFunc("/"+IndexFileName+".asc", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(sig)
+		})
+	}
+	// entries are named after metadata.yaml, not their directory, so map each
+	// name-version back to the sub-directory it was packaged from.
+	dirOf := map[string]string{}
+	dirs, _ := ioutil.ReadDir(s.Dir) // already read successfully by BuildIndex
+	for _, d := range dirs {
+		if meta, _, err := s.packageDefinition(d.Name()); err == nil && d.IsDir() {
+			dirOf[meta.Name+"-"+meta.Version] = d.Name()
+		}
+	}
+	for name, versions := range idx.Entries {
+		for _, e := range versions {
+			e := e
+			mux.HandleFunc(fmt.Sprintf("/%s-%s.tgz", name, e.Version), func(w http.ResponseWriter, r *http.Request) {
+				_, tarball, err := s.packageDefinition(dirOf[e.Name+"-"+e.Version])
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("Content-Type", "application/gzip")
+				_, _ = io.Copy(w, strings.NewReader(string(tarball)))
+			})
+		}
+	}
+	return mux, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package capabilities describes the view of a target cluster's feature set
+// that is injected into CUE template evaluation. It depends on neither
+// references/plugins nor pkg/cue, so that both references/plugins (which
+// discovers it) and pkg/cue (which injects it into the CUE evaluation
+// context) can depend on it without a cyclic import.
+package capabilities
+
+import (
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeversion "k8s.io/apimachinery/pkg/version"
+
+	"github.com/oam-dev/kubevela/pkg/oam/discoverymapper"
+	"github.com/oam-dev/kubevela/version"
+)
+
+// KubeVersion describes the version of the Kubernetes cluster a capability is
+// being rendered against, mirroring the shape of Helm's `.Capabilities.KubeVersion`.
+type KubeVersion struct {
+	Major      string
+	Minor      string
+	GitVersion string
+}
+
+// APIVersions is the set of `group/version` strings the cluster's API server
+// currently serves.
+type APIVersions map[string]bool
+
+// Has reports whether the cluster serves the given `group/version`, e.g.
+// `Capabilities.APIVersions.Has("autoscaling/v2")`.
+func (a APIVersions) Has(groupVersion string) bool {
+	return a[groupVersion]
+}
+
+// Capabilities is injected into the CUE evaluation context of every
+// ComponentDefinition/TraitDefinition template so CUE guards can branch on
+// what the target cluster actually supports. In CUE, APIVersions is bound as
+// a plain struct keyed by group/version, so a guard looks a key up directly,
+// e.g. `if Capabilities.APIVersions["autoscaling/v2"] { ... }`; the Has method
+// is available to Go callers only, not as a CUE function.
+type Capabilities struct {
+	KubeVersion KubeVersion
+	APIVersions APIVersions
+	VelaVersion string
+}
+
+// serverDiscoverer is the subset of discovery.DiscoveryInterface Discover
+// needs. discoverymapper.DiscoveryMapper's concrete implementation already
+// embeds a cached discovery client satisfying it, so Discover can reuse that
+// client instead of opening a second one against the same cluster.
+type serverDiscoverer interface {
+	ServerVersion() (*kubeversion.Info, error)
+	ServerGroups() (*metav1.APIGroupList, error)
+}
+
+// Discover builds the Capabilities object for the cluster dm was constructed
+// against, reusing dm's own discovery client rather than dialing a second one.
+func Discover(dm discoverymapper.DiscoveryMapper) (Capabilities, error) {
+	sd, ok := dm.(serverDiscoverer)
+	if !ok {
+		return Capabilities{}, errors.New("discovery mapper does not expose a server discovery client")
+	}
+	serverVersion, err := sd.ServerVersion()
+	if err != nil {
+		return Capabilities{}, err
+	}
+	groups, err := sd.ServerGroups()
+	if err != nil {
+		return Capabilities{}, err
+	}
+	apiVersions := APIVersions{}
+	for _, group := range groups.Groups {
+		for _, v := range group.Versions {
+			apiVersions[v.GroupVersion] = true
+		}
+	}
+	return Capabilities{
+		KubeVersion: KubeVersion{Major: serverVersion.Major, Minor: serverVersion.Minor, GitVersion: serverVersion.GitVersion},
+		APIVersions: apiVersions,
+		VelaVersion: version.VelaVersion,
+	}, nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package oci provides a thin client around ORAS for pulling KubeVela
+// capability definitions (ComponentDefinition/TraitDefinition bundles) that
+// are distributed through an OCI-compliant registry: it resolves a manifest
+// by reference, verifies the digest of its CUE template layer, and caches
+// the result on disk so repeated pulls of the same digest skip fetching the
+// template layer.
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/credentials"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// Scheme is the URI scheme used to identify an OCI-hosted capability source,
+// e.g. `oci://registry.example.com/kubevela/webservice:v1.2.0`.
+const Scheme = "oci://"
+
+// MediaTypeCUETemplate is the media type of the CUE template layer in a
+// capability bundle pushed to an OCI registry.
+const MediaTypeCUETemplate = "application/vnd.oam.vela.definition.cue.v1"
+
+// DefaultCacheDir is where pulled artifacts are cached, keyed by digest,
+// relative to the user's home directory.
+const DefaultCacheDir = ".vela/oci-cache"
+
+// Bundle is a capability definition resolved from an OCI registry.
+type Bundle struct {
+	// Digest is the manifest digest the bundle was resolved to.
+	Digest string `json:"digest"`
+	// CUETemplate is the raw CUE template layer of the bundle.
+	CUETemplate string `json:"cueTemplate"`
+}
+
+// IsOCIRef reports whether uri addresses an OCI registry source.
+func IsOCIRef(uri string) bool {
+	return strings.HasPrefix(uri, Scheme)
+}
+
+// Pull fetches a capability bundle from an OCI-compliant registry (Harbor,
+// GHCR, ECR, Docker Hub, ...), verifies the manifest and layer digests, and
+// caches the result under cacheDir keyed by digest. The manifest is still
+// resolved from the registry on every call; a cached digest only skips
+// fetching the template layer. An empty cacheDir defaults to ~/.vela/oci-cache.
+func Pull(ctx context.Context, ref string, cacheDir string) (*Bundle, error) {
+	if !IsOCIRef(ref) {
+		return nil, errors.Errorf("%s is not a valid oci:// reference", ref)
+	}
+	repoRef := strings.TrimPrefix(ref, Scheme)
+
+	if cacheDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, errors.Wrap(err, "resolve home directory for oci cache")
+		}
+		cacheDir = filepath.Join(home, DefaultCacheDir)
+	}
+
+	repo, err := remote.NewRepository(repoRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse oci reference %s", ref)
+	}
+	repo.Client = &auth.Client{Credential: credentialFromDockerConfig()}
+
+	_, manifestBytes, err := repo.FetchReference(ctx, repo.Reference.Reference)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolve manifest for %s", ref)
+	}
+	manifestDesc, err := repo.Resolve(ctx, repo.Reference.Reference)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolve manifest digest for %s", ref)
+	}
+	digest := manifestDesc.Digest.String()
+
+	if bundle, ok := readFromCache(cacheDir, digest); ok {
+		return bundle, nil
+	}
+
+	raw, err := content.ReadAll(manifestBytes, manifestDesc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read manifest for %s", ref)
+	}
+	var manifest specs.Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, errors.Wrapf(err, "decode manifest for %s", ref)
+	}
+
+	var cueTemplate []byte
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != MediaTypeCUETemplate {
+			continue
+		}
+		layerBytes, err := content.FetchAll(ctx, repo, layer)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetch cue template layer %s", layer.Digest)
+		}
+		if err := VerifyDigest(layerBytes, layer.Digest.String()); err != nil {
+			return nil, err
+		}
+		cueTemplate = layerBytes
+		break
+	}
+	if cueTemplate == nil {
+		return nil, errors.Errorf("no %s layer found in %s", MediaTypeCUETemplate, ref)
+	}
+
+	bundle := &Bundle{Digest: digest, CUETemplate: string(cueTemplate)}
+	if err := writeToCache(cacheDir, bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// credentialFromDockerConfig resolves basic/bearer auth for registries from
+// the user's ~/.docker/config.json, the same credential store the docker CLI
+// and Helm's OCI support use.
+func credentialFromDockerConfig() auth.CredentialFunc {
+	return func(ctx context.Context, registry string) (auth.Credential, error) {
+		cfg, err := config.Load(config.Dir())
+		if err != nil {
+			return auth.EmptyCredential, nil //nolint:nilerr // missing/unreadable config means anonymous pull
+		}
+		authConfig, err := cfg.GetAuthConfig(registry)
+		if err != nil {
+			return auth.EmptyCredential, nil //nolint:nilerr
+		}
+		switch {
+		case authConfig.IdentityToken != "":
+			return auth.Credential{RefreshToken: authConfig.IdentityToken}, nil
+		case authConfig.Username != "" || authConfig.Password != "":
+			return auth.Credential{Username: authConfig.Username, Password: authConfig.Password}, nil
+		default:
+			store := credentials.NewFileStore(cfg.ConfigFile)
+			if creds, err := store.Get(registry); err == nil {
+				return auth.Credential{Username: creds.Username, Password: creds.Password}, nil
+			}
+			return auth.EmptyCredential, nil
+		}
+	}
+}
+
+func cachePath(cacheDir, digest string) string {
+	return filepath.Join(cacheDir, strings.ReplaceAll(digest, ":", "_")+".json")
+}
+
+func readFromCache(cacheDir, digest string) (*Bundle, bool) {
+	b, err := ioutil.ReadFile(cachePath(cacheDir, digest)) //nolint:gosec // path is built from a content digest, not user input
+	if err != nil {
+		return nil, false
+	}
+	var bundle Bundle
+	if err := json.Unmarshal(b, &bundle); err != nil {
+		return nil, false
+	}
+	return &bundle, true
+}
+
+func writeToCache(cacheDir string, bundle *Bundle) error {
+	path := cachePath(cacheDir, bundle.Digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return errors.Wrap(err, "create oci cache directory")
+	}
+	b, err := json.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0640)
+}
+
+// VerifyDigest checks that the sha256 of content matches the expected digest
+// (formatted as "sha256:<hex>"), returning an error if they differ.
+func VerifyDigest(data []byte, expected string) error {
+	const prefix = "sha256:"
+	if !strings.HasPrefix(expected, prefix) {
+		return errors.Errorf("unsupported digest algorithm in %s", expected)
+	}
+	sum := sha256.Sum256(data)
+	got := prefix + hex.EncodeToString(sum[:])
+	if got != expected {
+		return errors.Errorf("digest mismatch: expected %s, got %s", expected, got)
+	}
+	return nil
+}
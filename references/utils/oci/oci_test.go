@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsOCIRef(t *testing.T) {
+	assert.True(t, IsOCIRef("oci://ghcr.io/org/webservice:v1"))
+	assert.False(t, IsOCIRef("https://example.com/webservice:v1"))
+	assert.False(t, IsOCIRef(""))
+}
+
+func TestVerifyDigest(t *testing.T) {
+	data := []byte("cue template body")
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	assert.NoError(t, VerifyDigest(data, digest))
+	assert.Error(t, VerifyDigest([]byte("tampered"), digest))
+	assert.Error(t, VerifyDigest(data, "md5:deadbeef"))
+}
+
+func TestCachePath(t *testing.T) {
+	path := cachePath("/tmp/oci-cache", "sha256:abcd1234")
+	assert.Equal(t, "/tmp/oci-cache/sha256_abcd1234.json", path)
+}
+
+func TestWriteAndReadFromCache(t *testing.T) {
+	dir := t.TempDir()
+	bundle := &Bundle{Digest: "sha256:feedface", CUETemplate: "parameter: {}"}
+
+	assert.NoError(t, writeToCache(dir, bundle))
+
+	got, ok := readFromCache(dir, bundle.Digest)
+	assert.True(t, ok)
+	assert.Equal(t, bundle, got)
+
+	_, ok = readFromCache(dir, "sha256:doesnotexist")
+	assert.False(t, ok)
+}